@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,34 +18,57 @@ import (
 
 	cm "github.com/chartmuseum/helm-push/pkg/chartmuseum"
 	"github.com/chartmuseum/helm-push/pkg/helm"
+	"github.com/chartmuseum/helm-push/pkg/ocipush"
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 type (
-	pushCmd struct {
-		chartName          string
-		chartVersion       string
-		repoName           string
+	// authFlags holds the repository connection flags shared by push,
+	// sync and mirror - auth/TLS settings that all of them resolve
+	// with the same CLI flag > repo entry > env var precedence
+	authFlags struct {
 		username           string
 		password           string
 		accessToken        string
 		authHeader         string
 		contextPath        string
-		forceUpload        bool
 		useHTTP            bool
 		caFile             string
 		certFile           string
 		keyFile            string
 		InsecureSkipVerify bool
+		PassCredentialsAll bool
+
+		// envPrefix is the upper-cased flag prefix (e.g. "SRC_" for
+		// the source side of `helm push mirror`), set by
+		// addAuthFlags so each authFlags instance reads its own
+		// HELM_REPO_* env vars instead of sharing the dest's.
+		envPrefix string
+	}
+
+	pushCmd struct {
+		authFlags
+		chartName      string
+		chartVersion   string
+		repoName       string
+		forceUpload    bool
+		sign           bool
+		key            string
+		keyring        string
+		passphraseFile string
+		provOnly       bool
+		verify         bool
+		plainHTTP      bool
 	}
 
 	config struct {
-		CurrentContext string             `json:"current-context"`
-		Contexts       map[string]context `json:"contexts"`
+		CurrentContext string                  `json:"current-context"`
+		Contexts       map[string]cfgEntryAuth `json:"contexts"`
 	}
 
-	context struct {
+	cfgEntryAuth struct {
 		Name  string `json:"name"`
 		Token string `json:"token"`
 	}
@@ -59,6 +83,9 @@ Examples:
   $ helm push . chartmuseum                       # package and push chart directory
   $ helm push . --version="7c4d121" chartmuseum   # override version in Chart.yaml
   $ helm push . https://my.chart.repo.com         # push directly to chart repo URL
+  $ helm push sync ./charts chartmuseum           # package and push every chart in a directory
+  $ helm push mirror https://src.repo dest-repo   # push only what dest-repo is missing from src
+  $ helm push . oci://registry.example.com/charts # push directly to an OCI registry
 `
 )
 
@@ -71,10 +98,18 @@ func newPushCmd(args []string) *cobra.Command {
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			// If there are 4 args, this is likely being used as a downloader for cm:// protocol
-			if len(args) == 4 && strings.HasPrefix(args[3], "cm://") {
+			// If there are 4 args, this is likely being used as a downloader for the
+			// cm:// or oci:// protocol. Helm's downloader protocol passes the repo
+			// entry's certFile, keyFile and caFile (as configured via
+			// `helm repo add --cert-file` etc.) ahead of the file URL.
+			if len(args) == 4 {
 				p.setFieldsFromEnv()
-				return p.download(args[3])
+				switch {
+				case strings.HasPrefix(args[3], "cm://"):
+					return p.download(args[0], args[1], args[2], args[3])
+				case strings.HasPrefix(args[3], "oci://"):
+					return p.downloadOCI(args[3])
+				}
 			}
 
 			if len(args) != 2 {
@@ -88,61 +123,198 @@ func newPushCmd(args []string) *cobra.Command {
 	}
 	f := cmd.Flags()
 	f.StringVarP(&p.chartVersion, "version", "v", "", "Override chart version pre-push")
-	f.StringVarP(&p.username, "username", "u", "", "Override HTTP basic auth username [$HELM_REPO_USERNAME]")
-	f.StringVarP(&p.password, "password", "p", "", "Override HTTP basic auth password [$HELM_REPO_PASSWORD]")
-	f.StringVarP(&p.accessToken, "access-token", "", "", "Send token in Authorization header [$HELM_REPO_ACCESS_TOKEN]")
-	f.StringVarP(&p.authHeader, "auth-header", "", "", "Alternative header to use for token auth [$HELM_REPO_AUTH_HEADER]")
-	f.StringVarP(&p.contextPath, "context-path", "", "", "ChartMuseum context path [$HELM_REPO_CONTEXT_PATH]")
-	//Appended for supporting https with certificates
-	f.StringVarP(&p.caFile, "ca-file", "", "", "Verify certificates of HTTPS-enabled servers using this CA bundle [$HELM_REPO_CA_FILE]")
-	f.StringVarP(&p.certFile, "cert-file", "", "", "Identify HTTPS client using this SSL certificate file [$HELM_REPO_CERT_FILE]")
-	f.StringVarP(&p.keyFile, "key-file", "", "", "Identify HTTPS client using this SSL key file [$HELM_REPO_KEY_FILE]")
-	f.BoolVarP(&p.InsecureSkipVerify, "insecure", "", false, "Connect to server with an insecure way by skipping certificate verification [$HELM_REPO_INSECURE]")
+	addAuthFlags(f, &p.authFlags, "")
 	f.BoolVarP(&p.forceUpload, "force", "f", false, "Force upload even if chart version exists")
+	f.BoolVarP(&p.sign, "sign", "", false, "Use a PGP private key to sign this chart")
+	f.StringVarP(&p.key, "key", "", "", "Name of the helm signing key to use")
+	f.StringVarP(&p.keyring, "keyring", "", defaultKeyring(), "Location of a secret keyring used for signing")
+	f.StringVarP(&p.passphraseFile, "passphrase-file", "", "", "Location of a file which contains the passphrase for the signing key")
+	f.BoolVarP(&p.provOnly, "prov-only", "", false, "Upload only the provenance file, skipping the chart package itself")
+	f.BoolVarP(&p.verify, "verify", "", false, "Verify the downloaded package against its provenance file before printing it")
+	f.BoolVarP(&p.plainHTTP, "plain-http", "", false, "Use plain HTTP (no TLS) when pushing to or pulling from an OCI registry")
 	f.Parse(args)
+
+	cmd.AddCommand(newSyncCmd())
+	cmd.AddCommand(newMirrorCmd())
+
 	return cmd
 }
 
-func (p *pushCmd) setFieldsFromEnv() {
-	if v, ok := os.LookupEnv("HELM_REPO_USERNAME"); ok && p.username == "" {
-		p.username = v
+// addAuthFlags registers the repository connection flags under the
+// given prefix (e.g. "src-" for the source side of `helm push mirror`),
+// binding them into a. Shorthands are only registered for the
+// unprefixed (destination) flag set, since pflag forbids reusing them.
+func addAuthFlags(f *pflag.FlagSet, a *authFlags, prefix string) {
+	a.envPrefix = envInfix(prefix)
+
+	shortUsername, shortPassword := "", ""
+	if prefix == "" {
+		shortUsername, shortPassword = "u", "p"
+	}
+	f.StringVarP(&a.username, prefix+"username", shortUsername, "", fmt.Sprintf("Override HTTP basic auth username [$HELM_REPO_%sUSERNAME]", envInfix(prefix)))
+	f.StringVarP(&a.password, prefix+"password", shortPassword, "", fmt.Sprintf("Override HTTP basic auth password [$HELM_REPO_%sPASSWORD]", envInfix(prefix)))
+	f.StringVarP(&a.accessToken, prefix+"access-token", "", "", fmt.Sprintf("Send token in Authorization header [$HELM_REPO_%sACCESS_TOKEN]", envInfix(prefix)))
+	f.StringVarP(&a.authHeader, prefix+"auth-header", "", "", fmt.Sprintf("Alternative header to use for token auth [$HELM_REPO_%sAUTH_HEADER]", envInfix(prefix)))
+	f.StringVarP(&a.contextPath, prefix+"context-path", "", "", fmt.Sprintf("ChartMuseum context path [$HELM_REPO_%sCONTEXT_PATH]", envInfix(prefix)))
+	f.StringVarP(&a.caFile, prefix+"ca-file", "", "", fmt.Sprintf("Verify certificates of HTTPS-enabled servers using this CA bundle [$HELM_REPO_%sCA_FILE]", envInfix(prefix)))
+	f.StringVarP(&a.certFile, prefix+"cert-file", "", "", fmt.Sprintf("Identify HTTPS client using this SSL certificate file [$HELM_REPO_%sCERT_FILE]", envInfix(prefix)))
+	f.StringVarP(&a.keyFile, prefix+"key-file", "", "", fmt.Sprintf("Identify HTTPS client using this SSL key file [$HELM_REPO_%sKEY_FILE]", envInfix(prefix)))
+	f.BoolVarP(&a.InsecureSkipVerify, prefix+"insecure", "", false, fmt.Sprintf("Connect to server with an insecure way by skipping certificate verification [$HELM_REPO_%sINSECURE]", envInfix(prefix)))
+	f.BoolVarP(&a.PassCredentialsAll, prefix+"pass-credentials", "", false, fmt.Sprintf("Pass credentials to all domains, including those redirected to [$HELM_REPO_%sPASS_CREDENTIALS]", envInfix(prefix)))
+}
+
+func envInfix(prefix string) string {
+	return strings.ToUpper(strings.Replace(prefix, "-", "_", -1))
+}
+
+// setFieldsFromEnv fills in the fields that have no repo-entry
+// equivalent straight from the environment once the CLI flag is known
+// to be blank. username/password/caFile/certFile/keyFile are
+// deliberately left untouched here: merging their env var in before
+// the repo entry is looked up would make resolveOverride unable to
+// tell a CLI flag apart from an env var, so the env var would always
+// beat the repo entry instead of only applying when the repo entry has
+// nothing either. Those fields are resolved against the repo entry
+// later, in credentialOptions.
+func (a *authFlags) setFieldsFromEnv() {
+	if v, ok := os.LookupEnv(a.envName("ACCESS_TOKEN")); ok && a.accessToken == "" {
+		a.accessToken = v
+	}
+	if v, ok := os.LookupEnv(a.envName("AUTH_HEADER")); ok && a.authHeader == "" {
+		a.authHeader = v
+	}
+	if v, ok := os.LookupEnv(a.envName("CONTEXT_PATH")); ok && a.contextPath == "" {
+		a.contextPath = v
+	}
+	if v, ok := os.LookupEnv(a.envName("USE_HTTP")); ok {
+		a.useHTTP, _ = strconv.ParseBool(v)
+	}
+	if v, ok := os.LookupEnv(a.envName("PASS_CREDENTIALS")); ok {
+		a.PassCredentialsAll, _ = strconv.ParseBool(v)
+	}
+
+	if a.accessToken == "" {
+		a.setAccessTokenFromConfigFile()
+	}
+}
+
+// envName builds the HELM_REPO_* variable name for suffix (e.g.
+// "USERNAME"), honoring a's flag prefix so the dest and src auth flags
+// registered by addAuthFlags each read their own env var, matching
+// what --help advertises for e.g. --src-username.
+func (a *authFlags) envName(suffix string) string {
+	return "HELM_REPO_" + a.envPrefix + suffix
+}
+
+// resolveOverride implements the credential precedence used throughout
+// this plugin: an explicit CLI flag always wins, a configured repo
+// entry (e.g. from `helm repo add --cert-file`) comes next, and the
+// HELM_REPO_* environment variable is only consulted if neither said
+// anything. It reports ok=false when there is nothing to override the
+// repo entry's own default with.
+func resolveOverride(cliValue, repoValue, envName string) (string, bool) {
+	if cliValue != "" {
+		return cliValue, true
+	}
+	if repoValue != "" {
+		return "", false
+	}
+	if v, ok := os.LookupEnv(envName); ok && v != "" {
+		return v, true
 	}
-	if v, ok := os.LookupEnv("HELM_REPO_PASSWORD"); ok && p.password == "" {
-		p.password = v
+	return "", false
+}
+
+// credentialOptions resolves CLI flag / repo entry / env var precedence
+// for every credential and TLS setting and returns the Options needed to
+// override repo's own defaults in cm.ClientFromRepoEntry.
+func (a *authFlags) credentialOptions(repo *helm.Repo) []cm.Option {
+	var opts []cm.Option
+
+	if v, ok := resolveOverride(a.username, repo.Username, a.envName("USERNAME")); ok {
+		opts = append(opts, cm.Username(v))
 	}
-	if v, ok := os.LookupEnv("HELM_REPO_ACCESS_TOKEN"); ok && p.accessToken == "" {
-		p.accessToken = v
+	if v, ok := resolveOverride(a.password, repo.Password, a.envName("PASSWORD")); ok {
+		opts = append(opts, cm.Password(v))
 	}
-	if v, ok := os.LookupEnv("HELM_REPO_AUTH_HEADER"); ok && p.authHeader == "" {
-		p.authHeader = v
+	if v, ok := resolveOverride(a.caFile, repo.CAFile, a.envName("CA_FILE")); ok {
+		opts = append(opts, cm.CAFile(v))
 	}
-	if v, ok := os.LookupEnv("HELM_REPO_CONTEXT_PATH"); ok && p.contextPath == "" {
-		p.contextPath = v
+	if v, ok := resolveOverride(a.certFile, repo.CertFile, a.envName("CERT_FILE")); ok {
+		opts = append(opts, cm.CertFile(v))
 	}
-	if v, ok := os.LookupEnv("HELM_REPO_USE_HTTP"); ok {
-		p.useHTTP, _ = strconv.ParseBool(v)
+	if v, ok := resolveOverride(a.keyFile, repo.KeyFile, a.envName("KEY_FILE")); ok {
+		opts = append(opts, cm.KeyFile(v))
+	}
+	if a.InsecureSkipVerify || repo.InsecureSkipTLSverify {
+		opts = append(opts, cm.InsecureSkipVerify(true))
+	}
+	if v, ok := os.LookupEnv(a.envName("INSECURE")); ok && !a.InsecureSkipVerify && !repo.InsecureSkipTLSverify {
+		if insecure, _ := strconv.ParseBool(v); insecure {
+			opts = append(opts, cm.InsecureSkipVerify(true))
+		}
 	}
 
-	//Appended for supporting https with certificates
-	if v, ok := os.LookupEnv("HELM_REPO_CA_FILE"); ok && p.caFile == "" {
-		p.caFile = v
+	if a.accessToken != "" {
+		opts = append(opts, cm.AccessToken(a.accessToken))
 	}
-	if v, ok := os.LookupEnv("HELM_REPO_CERT_FILE"); ok && p.certFile == "" {
-		p.certFile = v
+	if a.authHeader != "" {
+		opts = append(opts, cm.AuthHeader(a.authHeader))
 	}
-	if v, ok := os.LookupEnv("HELM_REPO_KEY_FILE"); ok && p.keyFile == "" {
-		p.keyFile = v
+	if a.contextPath != "" {
+		opts = append(opts, cm.ContextPath(a.contextPath))
 	}
-	if v, ok := os.LookupEnv("HELM_REPO_INSECURE"); ok {
-		p.InsecureSkipVerify, _ = strconv.ParseBool(v)
+	if a.PassCredentialsAll || repo.PassCredentialsAll {
+		opts = append(opts, cm.PassCredentialsAll(true))
+	}
+
+	return opts
+}
+
+// resolvedURL rewrites a repo's cm:// URL to http(s)://, per useHTTP
+func (a *authFlags) resolvedURL(rawURL string) string {
+	if a.useHTTP {
+		return strings.Replace(rawURL, "cm://", "http://", 1)
+	}
+	return strings.Replace(rawURL, "cm://", "https://", 1)
+}
+
+// repoURIPrefix matches any URI scheme (cm://, http(s)://, oci://, ...)
+// so a literal repo URL on the command line is never mistaken for a
+// configured repo name, regardless of which backend it targets.
+var repoURIPrefix = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://`)
+
+// resolveRepo looks up repoNameOrURL as a configured Helm repo entry,
+// or, if it already looks like a URL, builds a throwaway Repo for it -
+// without yet building a backend-specific client, so callers can decide
+// which backend (ChartMuseum, OCI, ...) to dispatch to first.
+func resolveRepo(repoNameOrURL string) (*helm.Repo, error) {
+	if repoURIPrefix.MatchString(repoNameOrURL) {
+		return helm.TempRepoFromURL(repoNameOrURL)
+	}
+	return helm.GetRepoByName(repoNameOrURL)
+}
+
+// clientForRepo resolves repo (by name or URL) into a connected Client,
+// applying a's credential precedence on top of whatever the repo entry
+// itself already has configured
+func (a *authFlags) clientForRepo(repoNameOrURL string) (*helm.Repo, *cm.Client, error) {
+	repo, err := resolveRepo(repoNameOrURL)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if p.accessToken == "" {
-		p.setAccessTokenFromConfigFile()
+	repo.URL = a.resolvedURL(repo.URL)
+
+	client, err := cm.ClientFromRepoEntry(repo, a.credentialOptions(repo)...)
+	if err != nil {
+		return nil, nil, err
 	}
+
+	return repo, client, nil
 }
 
-func (p *pushCmd) setAccessTokenFromConfigFile() {
+func (a *authFlags) setAccessTokenFromConfigFile() {
 	usr, err := user.Current()
 	if err != nil {
 		return
@@ -159,76 +331,168 @@ func (p *pushCmd) setAccessTokenFromConfigFile() {
 	if err = yaml.Unmarshal(yamlFile, &c); err != nil {
 		return
 	}
-	for _, context := range c.Contexts {
-		if context.Name == c.CurrentContext {
-			p.accessToken = context.Token
+	for _, ctx := range c.Contexts {
+		if ctx.Name == c.CurrentContext {
+			a.accessToken = ctx.Token
 			break
 		}
 	}
 }
 
 func (p *pushCmd) push() error {
-	var repo *helm.Repo
-	var err error
-
-	// If the argument looks like a URL, just create a temp repo object
-	// instead of looking for the entry in the local repository list
-	if regexp.MustCompile(`^https?://`).MatchString(p.repoName) {
-		repo, err = helm.TempRepoFromURL(p.repoName)
-		p.repoName = repo.URL
-	} else {
-		repo, err = helm.GetRepoByName(p.repoName)
+	repo, err := resolveRepo(p.repoName)
+	if err != nil {
+		return err
 	}
 
+	if strings.HasPrefix(repo.URL, "oci://") {
+		return p.pushOCI(repo)
+	}
+
+	_, client, err := p.clientForRepo(p.repoName)
 	if err != nil {
 		return err
 	}
 
+	return uploadOne(context.Background(), client, p.chartName, uploadOptions{
+		repoLabel:      p.repoName,
+		version:        p.chartVersion,
+		forceUpload:    p.forceUpload,
+		sign:           p.sign,
+		key:            p.key,
+		keyring:        p.keyring,
+		passphraseFile: p.passphraseFile,
+		provOnly:       p.provOnly,
+	})
+}
+
+// pushOCI packages (if necessary) chartName and pushes it as an OCI
+// artifact to repo, reusing the same username/password/access-token
+// flags as the ChartMuseum path
+func (p *pushCmd) pushOCI(repo *helm.Repo) error {
+	if p.sign || p.provOnly {
+		return errors.New("--sign and --prov-only are not supported when pushing to an oci:// registry")
+	}
+
 	chart, err := helm.GetChartByName(p.chartName)
 	if err != nil {
 		return err
 	}
-
-	// version override
 	if p.chartVersion != "" {
 		chart.SetVersion(p.chartVersion)
 	}
 
-	// username/password override(s)
-	username := repo.Username
-	password := repo.Password
+	tmp, err := ioutil.TempDir("", "helm-push-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	chartPackagePath, err := helm.CreateChartPackage(chart, tmp)
+	if err != nil {
+		return err
+	}
+
+	chartYAML, err := helm.ReadChartYAMLFromPackage(chartPackagePath)
+	if err != nil {
+		return err
+	}
+
+	ref := strings.TrimPrefix(repo.URL, "oci://") + "/" + chart.Name + ":" + chart.Version
+	client := ocipush.NewClient(ociRegistry(ref), p.ociOptions()...)
+
+	fmt.Printf("Pushing %s to %s...\n", filepath.Base(chartPackagePath), ref)
+	digest, err := client.Push(context.Background(), chartPackagePath, chartYAML, ref)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Pushed: %s\n", digest)
+	return nil
+}
+
+// downloadOCI pulls the chart layer referenced by fileURL (an oci://
+// reference) and streams it to stdout, mirroring download's behavior
+// for ChartMuseum-hosted charts
+func (p *pushCmd) downloadOCI(fileURL string) error {
+	if p.verify {
+		return errors.New("--verify is not supported when downloading from an oci:// registry")
+	}
+
+	ref := strings.TrimPrefix(fileURL, "oci://")
+	client := ocipush.NewClient(ociRegistry(ref), p.ociOptions()...)
+
+	b, err := client.Pull(context.Background(), ref)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(b))
+	return nil
+}
+
+// ociOptions carries the flags shared with the ChartMuseum path over to
+// ocipush.Client; the registry-config fallback for missing credentials
+// lives in ocipush.NewClient itself
+func (p *pushCmd) ociOptions() []ocipush.Option {
+	var opts []ocipush.Option
 	if p.username != "" {
-		username = p.username
+		opts = append(opts, ocipush.Username(p.username))
 	}
 	if p.password != "" {
-		password = p.password
+		opts = append(opts, ocipush.Password(p.password))
+	}
+	if p.accessToken != "" {
+		opts = append(opts, ocipush.AccessToken(p.accessToken))
 	}
+	if p.plainHTTP {
+		opts = append(opts, ocipush.PlainHTTP(true))
+	}
+	return opts
+}
 
-	// in case the repo is stored with cm:// protocol, remove it
-	var url string
-	if p.useHTTP {
-		url = strings.Replace(repo.URL, "cm://", "http://", 1)
-	} else {
-		url = strings.Replace(repo.URL, "cm://", "https://", 1)
-	}
-
-	client, err := cm.NewClient(
-		cm.URL(url),
-		cm.Username(username),
-		cm.Password(password),
-		cm.AccessToken(p.accessToken),
-		cm.AuthHeader(p.authHeader),
-		cm.ContextPath(p.contextPath),
-		cm.CAFile(p.caFile),
-		cm.CertFile(p.certFile),
-		cm.KeyFile(p.keyFile),
-		cm.InsecureSkipVerify(p.InsecureSkipVerify),
-	)
+// ociRegistry returns the registry host portion of an oci:// reference
+// with the scheme already stripped (e.g. "registry.example.com" out of
+// "registry.example.com/charts/mychart:1.2.3")
+func ociRegistry(ref string) string {
+	if idx := strings.Index(ref, "/"); idx >= 0 {
+		return ref[:idx]
+	}
+	return ref
+}
+
+type uploadOptions struct {
+	repoLabel      string
+	version        string
+	forceUpload    bool
+	sign           bool
+	key            string
+	keyring        string
+	passphraseFile string
+	provOnly       bool
+}
+
+// uploadOne packages (if necessary), optionally signs, and pushes a
+// single chart through client. It is the piece of pushCmd.push that
+// sync and mirror reuse to push many charts through one worker pool.
+func uploadOne(ctx context.Context, client *cm.Client, chartRef string, opts uploadOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if opts.provOnly && !opts.sign {
+		return errors.New("--prov-only requires --sign (there is no provenance file to upload otherwise)")
+	}
 
+	chart, err := helm.GetChartByName(chartRef)
 	if err != nil {
 		return err
 	}
 
+	if opts.version != "" {
+		chart.SetVersion(opts.version)
+	}
+
 	tmp, err := ioutil.TempDir("", "helm-push-")
 	if err != nil {
 		return err
@@ -240,8 +504,35 @@ func (p *pushCmd) push() error {
 		return err
 	}
 
-	fmt.Printf("Pushing %s to %s...\n", filepath.Base(chartPackagePath), p.repoName)
-	resp, err := client.UploadChartPackage(chartPackagePath, p.forceUpload)
+	var provPackagePath string
+	if opts.sign {
+		passphrase, err := readPassphraseFile(opts.passphraseFile)
+		if err != nil {
+			return err
+		}
+		provPackagePath, err = helm.SignChartPackage(chartPackagePath, opts.key, opts.keyring, passphrase)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !opts.provOnly {
+		fmt.Printf("Pushing %s to %s...\n", filepath.Base(chartPackagePath), opts.repoLabel)
+		resp, err := client.UploadChartPackage(chartPackagePath, opts.forceUpload)
+		if err != nil {
+			return err
+		}
+		if err := handlePushResponse(resp); err != nil {
+			return err
+		}
+	}
+
+	if provPackagePath == "" {
+		return nil
+	}
+
+	fmt.Printf("Pushing %s to %s...\n", filepath.Base(provPackagePath), opts.repoLabel)
+	resp, err := client.UploadProvenanceFile(provPackagePath)
 	if err != nil {
 		return err
 	}
@@ -249,7 +540,29 @@ func (p *pushCmd) push() error {
 	return handlePushResponse(resp)
 }
 
-func (p *pushCmd) download(fileURL string) error {
+func readPassphraseFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+func defaultKeyring() string {
+	if home, ok := os.LookupEnv("GNUPGHOME"); ok {
+		return filepath.Join(home, "secring.gpg")
+	}
+	usr, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(usr.HomeDir, ".gnupg", "secring.gpg")
+}
+
+func (p *pushCmd) download(certFile, keyFile, caFile, fileURL string) error {
 	parsedURL, err := url.Parse(fileURL)
 	if err != nil {
 		return err
@@ -277,19 +590,14 @@ func (p *pushCmd) download(fileURL string) error {
 		parsedURL.Scheme = "https"
 	}
 
-	client, err := cm.NewClient(
-		cm.URL(parsedURL.String()),
-		cm.Username(p.username),
-		cm.Password(p.password),
-		cm.AccessToken(p.accessToken),
-		cm.AuthHeader(p.authHeader),
-		cm.ContextPath(p.contextPath),
-		cm.CAFile(p.caFile),
-		cm.CertFile(p.certFile),
-		cm.KeyFile(p.keyFile),
-		cm.InsecureSkipVerify(p.InsecureSkipVerify),
-	)
+	repo := &helm.Repo{
+		URL:      parsedURL.String(),
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		CAFile:   caFile,
+	}
 
+	client, err := cm.ClientFromRepoEntry(repo, p.credentialOptions(repo)...)
 	if err != nil {
 		return err
 	}
@@ -299,9 +607,64 @@ func (p *pushCmd) download(fileURL string) error {
 		return err
 	}
 
+	b, err := handleDownloadResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	provBody, provErr := fetchProvenanceFile(client, filePath)
+	switch {
+	case provErr == nil:
+		provFilePath := filepath.Base(filePath) + ".prov"
+		if err := ioutil.WriteFile(provFilePath, provBody, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Saved provenance file to %s\n", provFilePath)
+
+		if p.verify {
+			if err := verifyDownloadedChart(b, provBody, filePath, p.keyring); err != nil {
+				return err
+			}
+		}
+	case p.verify:
+		return fmt.Errorf("could not fetch provenance file: %s", provErr)
+	}
+
+	fmt.Print(string(b))
+	return nil
+}
+
+// fetchProvenanceFile downloads the .prov sibling of filePath, if one
+// exists on the repo
+func fetchProvenanceFile(client *cm.Client, filePath string) ([]byte, error) {
+	resp, err := client.DownloadFile(filePath + ".prov")
+	if err != nil {
+		return nil, err
+	}
 	return handleDownloadResponse(resp)
 }
 
+// verifyDownloadedChart checks provBytes (the already-fetched
+// provenance file) against chartBytes using the configured keyring,
+// returning an error if verification fails.
+func verifyDownloadedChart(chartBytes, provBytes []byte, filePath, keyringPath string) error {
+	tmp, err := ioutil.TempDir("", "helm-push-verify-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	chartPath := filepath.Join(tmp, filepath.Base(filePath))
+	if err := ioutil.WriteFile(chartPath, chartBytes, 0644); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(chartPath+".prov", provBytes, 0644); err != nil {
+		return err
+	}
+
+	return helm.VerifyChartPackage(chartPath, chartPath+".prov", keyringPath)
+}
+
 func handlePushResponse(resp *http.Response) error {
 	if resp.StatusCode != 201 {
 		b, err := ioutil.ReadAll(resp.Body)
@@ -314,17 +677,16 @@ func handlePushResponse(resp *http.Response) error {
 	return nil
 }
 
-func handleDownloadResponse(resp *http.Response) error {
+func handleDownloadResponse(resp *http.Response) ([]byte, error) {
 	b, err := ioutil.ReadAll(resp.Body)
 	defer resp.Body.Close()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if resp.StatusCode != 200 {
-		return getChartmuseumError(b, resp.StatusCode)
+		return nil, getChartmuseumError(b, resp.StatusCode)
 	}
-	fmt.Print(string(b))
-	return nil
+	return b, nil
 }
 
 func getChartmuseumError(b []byte, code int) error {
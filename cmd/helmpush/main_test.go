@@ -0,0 +1,212 @@
+package main
+
+import (
+	"testing"
+
+	cm "github.com/chartmuseum/helm-push/pkg/chartmuseum"
+	"github.com/chartmuseum/helm-push/pkg/helm"
+)
+
+func TestResolveOverride(t *testing.T) {
+	cases := []struct {
+		name                string
+		cliValue, repoValue string
+		env                 string
+		wantValue           string
+		wantOK              bool
+	}{
+		{"cli wins over repo and env", "cli", "repo", "env", "cli", true},
+		{"repo wins over env when cli blank", "", "repo", "env", "", false},
+		{"env used only when cli and repo blank", "", "", "env", "env", true},
+		{"nothing set", "", "", "", "", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			const envName = "HELM_PUSH_TEST_RESOLVE_OVERRIDE"
+			if c.env != "" {
+				t.Setenv(envName, c.env)
+			}
+			v, ok := resolveOverride(c.cliValue, c.repoValue, envName)
+			if v != c.wantValue || ok != c.wantOK {
+				t.Errorf("resolveOverride(%q, %q, env=%q) = (%q, %v), want (%q, %v)", c.cliValue, c.repoValue, c.env, v, ok, c.wantValue, c.wantOK)
+			}
+		})
+	}
+}
+
+// TestCredentialPrecedenceSurvivesSetFieldsFromEnv guards against
+// setFieldsFromEnv folding HELM_REPO_USERNAME/CA_FILE/etc. into the
+// "CLI" slot before the repo entry is known, which would make them
+// indistinguishable from an actual --username/--ca-file flag and let
+// the env var silently outrank a configured repo entry.
+func TestCredentialPrecedenceSurvivesSetFieldsFromEnv(t *testing.T) {
+	t.Setenv("HELM_REPO_USERNAME", "env-user")
+	t.Setenv("HELM_REPO_CA_FILE", "env-ca.pem")
+
+	a := authFlags{}
+	a.setFieldsFromEnv()
+
+	repo := &helm.Repo{Username: "repo-user", CAFile: "repo-ca.pem"}
+
+	if v, ok := resolveOverride(a.username, repo.Username, "HELM_REPO_USERNAME"); ok || v != "" {
+		t.Errorf("username override = (%q, %v), want repo entry to win (\"\", false)", v, ok)
+	}
+	if v, ok := resolveOverride(a.caFile, repo.CAFile, "HELM_REPO_CA_FILE"); ok || v != "" {
+		t.Errorf("caFile override = (%q, %v), want repo entry to win (\"\", false)", v, ok)
+	}
+}
+
+// TestCredentialOptions exercises credentialOptions's username/password
+// resolution across the CLI flag / repo entry / env var precedence,
+// building the Client through cm.ClientFromRepoEntry the same way
+// push/sync/mirror do - credentialOptions only returns override
+// Options, so the repo entry's own defaults have to come from
+// ClientFromRepoEntry's base Options, not from credentialOptions alone.
+func TestCredentialOptions(t *testing.T) {
+	cases := []struct {
+		name     string
+		cli      authFlags
+		repo     helm.Repo
+		env      map[string]string
+		wantUser string
+		wantPass string
+	}{
+		{
+			name:     "repo entry used when no CLI flag or env var is set",
+			repo:     helm.Repo{Username: "repo-user", Password: "repo-pass"},
+			wantUser: "repo-user", wantPass: "repo-pass",
+		},
+		{
+			name:     "CLI flag wins over repo entry and env",
+			cli:      authFlags{username: "cli-user", password: "cli-pass"},
+			repo:     helm.Repo{Username: "repo-user", Password: "repo-pass"},
+			env:      map[string]string{"HELM_REPO_USERNAME": "env-user", "HELM_REPO_PASSWORD": "env-pass"},
+			wantUser: "cli-user", wantPass: "cli-pass",
+		},
+		{
+			name:     "env var does not override a configured repo entry",
+			repo:     helm.Repo{Username: "repo-user", Password: "repo-pass"},
+			env:      map[string]string{"HELM_REPO_USERNAME": "env-user", "HELM_REPO_PASSWORD": "env-pass"},
+			wantUser: "repo-user", wantPass: "repo-pass",
+		},
+		{
+			name:     "env var used only when neither CLI flag nor repo entry has anything",
+			env:      map[string]string{"HELM_REPO_USERNAME": "env-user", "HELM_REPO_PASSWORD": "env-pass"},
+			wantUser: "env-user", wantPass: "env-pass",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+
+			client, err := cm.ClientFromRepoEntry(&c.repo, c.cli.credentialOptions(&c.repo)...)
+			if err != nil {
+				t.Fatalf("ClientFromRepoEntry: %s", err)
+			}
+
+			if client.Username != c.wantUser {
+				t.Errorf("Username = %q, want %q", client.Username, c.wantUser)
+			}
+			if client.Password != c.wantPass {
+				t.Errorf("Password = %q, want %q", client.Password, c.wantPass)
+			}
+		})
+	}
+}
+
+// TestResolveOverrideAcrossTLSFields exercises the same CLI flag / repo
+// entry / env var precedence resolveOverride implements, against the
+// actual field and env var names credentialOptions wires up for every
+// TLS setting - CAFile/CertFile/KeyFile are checked directly through
+// resolveOverride rather than through ClientFromRepoEntry, since the
+// latter reads the paths off disk to build a tls.Config.
+func TestResolveOverrideAcrossTLSFields(t *testing.T) {
+	cases := []struct {
+		name                      string
+		cliCA, cliCert, cliKey    string
+		repo                      helm.Repo
+		env                       map[string]string
+		wantCA, wantCert, wantKey string
+		wantOK                    bool
+	}{
+		{
+			name:   "repo entry used when no CLI flag or env var is set",
+			repo:   helm.Repo{CAFile: "repo-ca.pem", CertFile: "repo-cert.pem", KeyFile: "repo-key.pem"},
+			wantOK: false,
+		},
+		{
+			name:    "CLI flag wins over repo entry and env",
+			cliCA:   "cli-ca.pem",
+			cliCert: "cli-cert.pem",
+			cliKey:  "cli-key.pem",
+			repo:    helm.Repo{CAFile: "repo-ca.pem", CertFile: "repo-cert.pem", KeyFile: "repo-key.pem"},
+			env:     map[string]string{"HELM_REPO_CA_FILE": "env-ca.pem", "HELM_REPO_CERT_FILE": "env-cert.pem", "HELM_REPO_KEY_FILE": "env-key.pem"},
+			wantCA:  "cli-ca.pem", wantCert: "cli-cert.pem", wantKey: "cli-key.pem",
+			wantOK: true,
+		},
+		{
+			name:   "env var does not override a configured repo entry",
+			repo:   helm.Repo{CAFile: "repo-ca.pem", CertFile: "repo-cert.pem", KeyFile: "repo-key.pem"},
+			env:    map[string]string{"HELM_REPO_CA_FILE": "env-ca.pem", "HELM_REPO_CERT_FILE": "env-cert.pem", "HELM_REPO_KEY_FILE": "env-key.pem"},
+			wantOK: false,
+		},
+		{
+			name:   "env var used only when neither CLI flag nor repo entry has anything",
+			env:    map[string]string{"HELM_REPO_CA_FILE": "env-ca.pem", "HELM_REPO_CERT_FILE": "env-cert.pem", "HELM_REPO_KEY_FILE": "env-key.pem"},
+			wantCA: "env-ca.pem", wantCert: "env-cert.pem", wantKey: "env-key.pem",
+			wantOK: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+
+			if v, ok := resolveOverride(c.cliCA, c.repo.CAFile, "HELM_REPO_CA_FILE"); v != c.wantCA || ok != c.wantOK {
+				t.Errorf("CAFile override = (%q, %v), want (%q, %v)", v, ok, c.wantCA, c.wantOK)
+			}
+			if v, ok := resolveOverride(c.cliCert, c.repo.CertFile, "HELM_REPO_CERT_FILE"); v != c.wantCert || ok != c.wantOK {
+				t.Errorf("CertFile override = (%q, %v), want (%q, %v)", v, ok, c.wantCert, c.wantOK)
+			}
+			if v, ok := resolveOverride(c.cliKey, c.repo.KeyFile, "HELM_REPO_KEY_FILE"); v != c.wantKey || ok != c.wantOK {
+				t.Errorf("KeyFile override = (%q, %v), want (%q, %v)", v, ok, c.wantKey, c.wantOK)
+			}
+		})
+	}
+}
+
+// TestCredentialOptionsSrcEnvIsIndependentOfDest guards against the
+// src and dest authFlags instances registered for `helm push mirror`
+// reading the same HELM_REPO_* env var - addAuthFlags advertises
+// $HELM_REPO_SRC_USERNAME for --src-username, so it must not also be
+// satisfied by (or leak into) the plain $HELM_REPO_USERNAME read by
+// the destination's authFlags.
+func TestCredentialOptionsSrcEnvIsIndependentOfDest(t *testing.T) {
+	t.Setenv("HELM_REPO_USERNAME", "dest-env-user")
+	t.Setenv("HELM_REPO_SRC_USERNAME", "src-env-user")
+
+	dest := authFlags{envPrefix: envInfix("")}
+	src := authFlags{envPrefix: envInfix("src-")}
+
+	destClient := &cm.Client{}
+	for _, opt := range dest.credentialOptions(&helm.Repo{}) {
+		opt(destClient)
+	}
+	srcClient := &cm.Client{}
+	for _, opt := range src.credentialOptions(&helm.Repo{}) {
+		opt(srcClient)
+	}
+
+	if destClient.Username != "dest-env-user" {
+		t.Errorf("dest Username = %q, want %q", destClient.Username, "dest-env-user")
+	}
+	if srcClient.Username != "src-env-user" {
+		t.Errorf("src Username = %q, want %q", srcClient.Username, "src-env-user")
+	}
+}
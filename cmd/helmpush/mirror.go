@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	cm "github.com/chartmuseum/helm-push/pkg/chartmuseum"
+	"github.com/chartmuseum/helm-push/pkg/helm"
+	"github.com/spf13/cobra"
+)
+
+type mirrorCmd struct {
+	authFlags
+	srcAuth     authFlags
+	srcRepoURL  string
+	destRepo    string
+	parallelism int
+	forceUpload bool
+}
+
+func newMirrorCmd() *cobra.Command {
+	m := &mirrorCmd{}
+	cmd := &cobra.Command{
+		Use:          "mirror <srcRepoURL> <destRepo>",
+		Short:        "Push every chart version present in srcRepoURL but missing from destRepo",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("this command needs 2 arguments: source repository URL, name of destination chart repository (or repo URL)")
+			}
+			m.srcRepoURL = args[0]
+			m.destRepo = args[1]
+			m.setFieldsFromEnv()
+			m.srcAuth.setFieldsFromEnv()
+			return m.run()
+		},
+	}
+	f := cmd.Flags()
+	addAuthFlags(f, &m.authFlags, "")
+	addAuthFlags(f, &m.srcAuth, "src-")
+	f.IntVarP(&m.parallelism, "parallelism", "", 4, "Number of charts to push concurrently")
+	f.BoolVarP(&m.forceUpload, "force", "f", false, "Re-push chart versions that already exist in the destination repo")
+	return cmd
+}
+
+func (m *mirrorCmd) run() error {
+	srcRepo, err := helm.TempRepoFromURL(m.srcRepoURL)
+	if err != nil {
+		return err
+	}
+	srcRepo.URL = m.srcAuth.resolvedURL(srcRepo.URL)
+
+	srcClient, err := cm.ClientFromRepoEntry(srcRepo, m.srcAuth.credentialOptions(srcRepo)...)
+	if err != nil {
+		return err
+	}
+
+	srcIndex, err := srcClient.GetIndex()
+	if err != nil {
+		return fmt.Errorf("could not fetch source index.yaml: %s", err)
+	}
+
+	destRepo, destClient, err := m.clientForRepo(m.destRepo)
+	if err != nil {
+		return err
+	}
+
+	destIndex, err := indexUnlessForced(destClient, destRepo.URL, m.forceUpload)
+	if err != nil {
+		return err
+	}
+
+	missing := srcIndex.Missing(destIndex)
+	if len(missing) == 0 {
+		fmt.Println("destination already has every chart version from the source, nothing to do")
+		return nil
+	}
+
+	labels, byLabel := labelMissingCharts(missing)
+
+	result := pushAll(labels, m.parallelism, func(ctx context.Context, label string) (string, error) {
+		return mirrorOne(ctx, srcClient, destClient, srcRepo.URL, byLabel[label], destRepo.URL, m.forceUpload)
+	})
+
+	result.summarize()
+	if result.failed > 0 {
+		return fmt.Errorf("%d chart(s) failed to mirror", result.failed)
+	}
+	return nil
+}
+
+// labelMissingCharts assigns each missing chart version a unique label
+// for pushAll and returns a map back from label to chart version.
+// Each label is prefixed with the entry's position in missing, so it
+// stays unique even where two entries format to the same string: chart
+// names may contain dashes, so e.g. name="foo-1", version="0" and
+// name="foo", version="1-0" would otherwise collapse onto the same
+// "foo-1-0" label and silently drop one of the two charts from the run.
+func labelMissingCharts(missing []cm.IndexChartVersion) ([]string, map[string]cm.IndexChartVersion) {
+	type chartKey struct {
+		Name, Version string
+	}
+	byKey := make(map[chartKey]cm.IndexChartVersion, len(missing))
+	labels := make([]string, len(missing))
+	byLabel := make(map[string]cm.IndexChartVersion, len(missing))
+	for i, v := range missing {
+		k := chartKey{Name: v.Name, Version: v.Version}
+		byKey[k] = v
+		label := fmt.Sprintf("%d:%s-%s", i, v.Name, v.Version)
+		labels[i] = label
+		byLabel[label] = byKey[k]
+	}
+	return labels, byLabel
+}
+
+// mirrorOne downloads a single chart version from src and pushes the
+// tarball to dest through the same uploadOne path push and sync use
+func mirrorOne(ctx context.Context, src, dest *cm.Client, srcRepoURL string, entry cm.IndexChartVersion, destLabel string, forceUpload bool) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if len(entry.URLs) == 0 {
+		return "", fmt.Errorf("%s-%s has no download URLs in the source index", entry.Name, entry.Version)
+	}
+
+	chartURL, err := resolveChartURL(srcRepoURL, entry.URLs[0])
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := src.DownloadFileAt(chartURL)
+	if err != nil {
+		return "", err
+	}
+	b, err := handleDownloadResponse(resp)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := ioutil.TempDir("", "helm-push-mirror-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	chartPath := filepath.Join(tmp, fmt.Sprintf("%s-%s.tgz", entry.Name, entry.Version))
+	if err := ioutil.WriteFile(chartPath, b, 0644); err != nil {
+		return "", err
+	}
+
+	if err := uploadOne(ctx, dest, chartPath, uploadOptions{repoLabel: destLabel, forceUpload: forceUpload}); err != nil {
+		return "", err
+	}
+
+	return "pushed", nil
+}
+
+// resolveChartURL resolves a chart's "urls" entry against the
+// repository root, since index.yaml may list either absolute URLs or
+// ones relative to the repo
+func resolveChartURL(repoURL, rawURL string) (string, error) {
+	base, err := url.Parse(repoURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	cm "github.com/chartmuseum/helm-push/pkg/chartmuseum"
+)
+
+// TestLabelMissingChartsDedupesByNameAndVersion guards against the
+// label/key collapsing two distinct charts that would format to the
+// same "name-version" string, e.g. name="foo-1", version="0" and
+// name="foo", version="1-0" both rendering as "foo-1-0".
+func TestLabelMissingChartsDedupesByNameAndVersion(t *testing.T) {
+	missing := []cm.IndexChartVersion{
+		{Name: "foo-1", Version: "0"},
+		{Name: "foo", Version: "1-0"},
+	}
+
+	labels, byLabel := labelMissingCharts(missing)
+
+	if len(labels) != len(missing) {
+		t.Fatalf("got %d labels, want %d", len(labels), len(missing))
+	}
+	if len(byLabel) != len(missing) {
+		t.Fatalf("got %d entries in byLabel, want %d", len(byLabel), len(missing))
+	}
+
+	seen := make(map[string]bool)
+	for _, label := range labels {
+		if seen[label] {
+			t.Fatalf("duplicate label %q, labels must be unique", label)
+		}
+		seen[label] = true
+
+		entry, ok := byLabel[label]
+		if !ok {
+			t.Fatalf("label %q missing from byLabel", label)
+		}
+		if entry.Name != missing[0].Name && entry.Name != missing[1].Name {
+			t.Fatalf("byLabel[%q] = %+v, does not match either input entry", label, entry)
+		}
+	}
+}
+
+func TestLabelMissingChartsRoundTrips(t *testing.T) {
+	missing := []cm.IndexChartVersion{
+		{Name: "mychart", Version: "0.1.0", URLs: []string{"mychart-0.1.0.tgz"}},
+		{Name: "otherchart", Version: "2.0.0", URLs: []string{"otherchart-2.0.0.tgz"}},
+	}
+
+	labels, byLabel := labelMissingCharts(missing)
+
+	for i, label := range labels {
+		got, ok := byLabel[label]
+		if !ok {
+			t.Fatalf("label %q not found in byLabel", label)
+		}
+		if got.Name != missing[i].Name || got.Version != missing[i].Version {
+			t.Errorf("byLabel[%q] = %+v, want %+v", label, got, missing[i])
+		}
+	}
+}
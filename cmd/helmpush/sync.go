@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cm "github.com/chartmuseum/helm-push/pkg/chartmuseum"
+	"github.com/chartmuseum/helm-push/pkg/helm"
+	"github.com/spf13/cobra"
+)
+
+type syncCmd struct {
+	authFlags
+	srcDir      string
+	repoName    string
+	parallelism int
+	forceUpload bool
+}
+
+func newSyncCmd() *cobra.Command {
+	s := &syncCmd{}
+	cmd := &cobra.Command{
+		Use:          "sync <srcDir> <repo>",
+		Short:        "Package and push every chart found under srcDir",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("this command needs 2 arguments: source directory, name of chart repository (or repo URL)")
+			}
+			s.srcDir = args[0]
+			s.repoName = args[1]
+			s.setFieldsFromEnv()
+			return s.run()
+		},
+	}
+	f := cmd.Flags()
+	addAuthFlags(f, &s.authFlags, "")
+	f.IntVarP(&s.parallelism, "parallelism", "", 4, "Number of charts to push concurrently")
+	f.BoolVarP(&s.forceUpload, "force", "f", false, "Re-push chart versions that already exist in the destination repo")
+	return cmd
+}
+
+func (s *syncCmd) run() error {
+	refs, err := findChartsUnder(s.srcDir)
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		return fmt.Errorf("no charts found under %s", s.srcDir)
+	}
+
+	repo, client, err := s.clientForRepo(s.repoName)
+	if err != nil {
+		return err
+	}
+
+	index, err := indexUnlessForced(client, repo.URL, s.forceUpload)
+	if err != nil {
+		return err
+	}
+
+	result := pushAll(refs, s.parallelism, func(ctx context.Context, ref string) (string, error) {
+		return uploadIfMissing(ctx, client, ref, index, uploadOptions{
+			repoLabel:   repo.URL,
+			forceUpload: s.forceUpload,
+		})
+	})
+
+	result.summarize()
+	if result.failed > 0 {
+		return fmt.Errorf("%d chart(s) failed to push", result.failed)
+	}
+	return nil
+}
+
+// findChartsUnder walks dir for packaged charts (*.tgz) and unpacked
+// chart directories (anything containing a Chart.yaml), without
+// descending into a chart directory once it has been identified as one
+func findChartsUnder(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []string
+	for _, entry := range entries {
+		full := filepath.Join(dir, entry.Name())
+
+		if entry.IsDir() {
+			if _, err := os.Stat(filepath.Join(full, "Chart.yaml")); err == nil {
+				refs = append(refs, full)
+				continue
+			}
+			nested, err := findChartsUnder(full)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, nested...)
+			continue
+		}
+
+		if strings.HasSuffix(entry.Name(), ".tgz") {
+			refs = append(refs, full)
+		}
+	}
+
+	return refs, nil
+}
+
+// indexUnlessForced fetches the destination repo's index.yaml so
+// callers can skip chart versions that are already present, unless
+// force is set. A failure to fetch the index is non-fatal: every chart
+// is simply pushed, same as a brand new repo would require.
+func indexUnlessForced(client *cm.Client, repoLabel string, force bool) (*cm.Index, error) {
+	if force {
+		return nil, nil
+	}
+
+	index, err := client.GetIndex()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not fetch %s/index.yaml, pushing every chart: %s\n", repoLabel, err)
+		return nil, nil
+	}
+	return index, nil
+}
+
+// uploadIfMissing pushes ref through client unless its name/version is
+// already present in index
+func uploadIfMissing(ctx context.Context, client *cm.Client, ref string, index *cm.Index, opts uploadOptions) (string, error) {
+	if index != nil {
+		if chart, err := helm.GetChartByName(ref); err == nil && chart.Name != "" && chart.Version != "" {
+			if index.Has(chart.Name, chart.Version) {
+				fmt.Printf("Skipping %s-%s, already present in %s\n", chart.Name, chart.Version, opts.repoLabel)
+				return "skipped", nil
+			}
+		}
+	}
+
+	if err := uploadOne(ctx, client, ref, opts); err != nil {
+		return "", err
+	}
+	return "pushed", nil
+}
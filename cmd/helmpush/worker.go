@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// pushResult tallies the outcome of a batch of uploads run through pushAll
+type pushResult struct {
+	mu                      sync.Mutex
+	pushed, skipped, failed int
+}
+
+func (r *pushResult) record(status string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case err != nil:
+		r.failed++
+	case status == "skipped":
+		r.skipped++
+	default:
+		r.pushed++
+	}
+}
+
+func (r *pushResult) summarize() {
+	fmt.Printf("Summary: %d pushed, %d skipped, %d failed\n", r.pushed, r.skipped, r.failed)
+}
+
+// pushAll runs work over items with up to parallelism concurrent
+// workers, collecting a summary of pushes, skips and failures. Every
+// item is attempted even if others fail - a failure is logged to
+// stderr but does not stop the batch.
+func pushAll(items []string, parallelism int, work func(ctx context.Context, item string) (string, error)) *pushResult {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	result := &pushResult{}
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for _, item := range items {
+		item := item
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			status, err := work(context.Background(), item)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to push %s: %s\n", item, err)
+			}
+			result.record(status, err)
+		}()
+	}
+
+	wg.Wait()
+	return result
+}
@@ -0,0 +1,321 @@
+package chartmuseum
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type (
+	// Client talks to a ChartMuseum-compatible chart repository
+	Client struct {
+		URL                string
+		SourceURL          string
+		Username           string
+		Password           string
+		AccessToken        string
+		AuthHeader         string
+		ContextPath        string
+		CAFile             string
+		CertFile           string
+		KeyFile            string
+		InsecureSkipVerify bool
+		PassCredentialsAll bool
+		Client             *http.Client
+	}
+
+	// Option configures a Client
+	Option func(*Client)
+)
+
+// URL sets the base URL of the chart repository
+func URL(url string) Option {
+	return func(c *Client) { c.URL = url }
+}
+
+// Username sets the HTTP basic auth username
+func Username(username string) Option {
+	return func(c *Client) { c.Username = username }
+}
+
+// Password sets the HTTP basic auth password
+func Password(password string) Option {
+	return func(c *Client) { c.Password = password }
+}
+
+// AccessToken sets a bearer token to send in the Authorization header
+func AccessToken(accessToken string) Option {
+	return func(c *Client) { c.AccessToken = accessToken }
+}
+
+// AuthHeader sets an alternative header name to use for token auth
+func AuthHeader(authHeader string) Option {
+	return func(c *Client) { c.AuthHeader = authHeader }
+}
+
+// ContextPath sets a path prefix under which the ChartMuseum API is served
+func ContextPath(contextPath string) Option {
+	return func(c *Client) { c.ContextPath = contextPath }
+}
+
+// CAFile sets the CA bundle used to verify the server's certificate
+func CAFile(caFile string) Option {
+	return func(c *Client) { c.CAFile = caFile }
+}
+
+// CertFile sets the client certificate used for mutual TLS
+func CertFile(certFile string) Option {
+	return func(c *Client) { c.CertFile = certFile }
+}
+
+// KeyFile sets the client key used for mutual TLS
+func KeyFile(keyFile string) Option {
+	return func(c *Client) { c.KeyFile = keyFile }
+}
+
+// InsecureSkipVerify disables server certificate verification
+func InsecureSkipVerify(insecure bool) Option {
+	return func(c *Client) { c.InsecureSkipVerify = insecure }
+}
+
+// SourceURL records the repository URL the credentials were configured
+// for, so requests to any other host (e.g. after a redirect) can be
+// recognized as cross-host and have credentials withheld. Defaults to
+// URL when not set.
+func SourceURL(sourceURL string) Option {
+	return func(c *Client) { c.SourceURL = sourceURL }
+}
+
+// PassCredentialsAll forwards basic auth, the access token, and the
+// configured auth header to any host the request ends up hitting,
+// including across redirects. Mirrors Helm's --pass-credentials flag;
+// leave this false unless the repo is known to redirect to a trusted host.
+func PassCredentialsAll(pass bool) Option {
+	return func(c *Client) { c.PassCredentialsAll = pass }
+}
+
+// NewClient constructs a Client, applying the given Options and
+// configuring TLS if any certificate-related option was supplied
+func NewClient(opts ...Option) (*Client, error) {
+	c := &Client{
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.SourceURL == "" {
+		c.SourceURL = c.URL
+	}
+
+	tlsConfig, err := tlsConfigFor(c)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		c.Client.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+
+	c.Client.CheckRedirect = c.checkRedirect
+
+	return c, nil
+}
+
+// checkRedirect strips credentials before following a redirect to a
+// different host, unless PassCredentialsAll was requested. This closes
+// the same credential-leak hole fixed in Helm 3.6 (CVE-2021-32690): a
+// ChartMuseum-compatible server (or anything in front of it) can 302 a
+// request to an arbitrary third-party host and, without this check,
+// Go's http.Client would otherwise happily resend the Authorization
+// header there.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	if !c.PassCredentialsAll && !hostsMatch(c.SourceURL, req.URL.String()) {
+		req.Header.Del("Authorization")
+		if c.AuthHeader != "" {
+			req.Header.Del(c.AuthHeader)
+		}
+	}
+	return nil
+}
+
+func tlsConfigFor(c *Client) (*tls.Config, error) {
+	if c.CAFile == "" && c.CertFile == "" && c.KeyFile == "" && !c.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+
+	if c.CAFile != "" {
+		caCert, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("could not parse CA file: %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.CertFile != "" && c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// applyAuth attaches credentials to req, but only when req targets the
+// same host the credentials were configured for, or PassCredentialsAll
+// was requested. See checkRedirect for the equivalent check on the
+// redirect path.
+func (c *Client) applyAuth(req *http.Request) {
+	if !c.PassCredentialsAll && !hostsMatch(c.SourceURL, req.URL.String()) {
+		return
+	}
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	if c.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	}
+	if c.AuthHeader != "" {
+		req.Header.Set(c.AuthHeader, c.AccessToken)
+	}
+}
+
+// hostsMatch reports whether a and b refer to the same host, comparing
+// hostname and port case-insensitively and treating a scheme's default
+// port as equivalent to no port being specified at all.
+func hostsMatch(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(normalizeHostPort(ua), normalizeHostPort(ub))
+}
+
+func normalizeHostPort(u *url.URL) string {
+	host := u.Hostname()
+	port := u.Port()
+	if port == "" {
+		return host
+	}
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		return host
+	}
+	return host + ":" + port
+}
+
+func (c *Client) apiBase() string {
+	base := c.URL
+	if c.ContextPath != "" {
+		base = base + c.ContextPath
+	}
+	return base
+}
+
+// UploadChartPackage POSTs the chart tarball at path to the /api/charts
+// endpoint, optionally forcing the upload if the version already exists
+func (c *Client) UploadChartPackage(path string, force bool) (*http.Response, error) {
+	body, contentType, err := chartMultipartBody("chart", path)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := c.apiBase() + "/api/charts"
+	if force {
+		endpoint = endpoint + "?force"
+	}
+
+	req, err := http.NewRequest("POST", endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	c.applyAuth(req)
+
+	return c.Client.Do(req)
+}
+
+// UploadProvenanceFile POSTs a chart's .prov file to the /api/prov
+// endpoint, alongside (or instead of, for --prov-only pushes) the
+// chart tarball itself
+func (c *Client) UploadProvenanceFile(path string) (*http.Response, error) {
+	body, contentType, err := chartMultipartBody("prov", path)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", c.apiBase()+"/api/prov", body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	c.applyAuth(req)
+
+	return c.Client.Do(req)
+}
+
+// DownloadFile fetches filePath (relative to the repository root) from
+// the chart repository
+func (c *Client) DownloadFile(filePath string) (*http.Response, error) {
+	return c.DownloadFileAt(c.URL + "/" + filePath)
+}
+
+// DownloadFileAt fetches rawURL directly, applying the same credential
+// rules as DownloadFile. Used for chart repository index.yaml "urls"
+// entries, which may already be absolute.
+func (c *Client) DownloadFileAt(rawURL string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.applyAuth(req)
+
+	return c.Client.Do(req)
+}
+
+func chartMultipartBody(field, path string) (io.Reader, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return nil, "", err
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return &buf, w.FormDataContentType(), nil
+}
@@ -0,0 +1,93 @@
+package chartmuseum
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDownloadFileDoesNotLeakCredentialsCrossHost(t *testing.T) {
+	var sawAuthHeader bool
+	thirdParty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			sawAuthHeader = true
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer thirdParty.Close()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, thirdParty.URL+"/mychart-0.1.0.tgz", http.StatusFound)
+	}))
+	defer origin.Close()
+
+	client, err := NewClient(
+		URL(origin.URL),
+		Username("admin"),
+		Password("secret"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+
+	if _, err := client.DownloadFile("mychart-0.1.0.tgz"); err != nil {
+		t.Fatalf("DownloadFile: %s", err)
+	}
+
+	if sawAuthHeader {
+		t.Fatal("expected Authorization header to be stripped on cross-host redirect")
+	}
+}
+
+func TestDownloadFilePassCredentialsAllForwardsAcrossHosts(t *testing.T) {
+	var sawAuthHeader bool
+	thirdParty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			sawAuthHeader = true
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer thirdParty.Close()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, thirdParty.URL+"/mychart-0.1.0.tgz", http.StatusFound)
+	}))
+	defer origin.Close()
+
+	client, err := NewClient(
+		URL(origin.URL),
+		Username("admin"),
+		Password("secret"),
+		PassCredentialsAll(true),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+
+	if _, err := client.DownloadFile("mychart-0.1.0.tgz"); err != nil {
+		t.Fatalf("DownloadFile: %s", err)
+	}
+
+	if !sawAuthHeader {
+		t.Fatal("expected Authorization header to be forwarded when PassCredentialsAll is set")
+	}
+}
+
+func TestHostsMatch(t *testing.T) {
+	cases := []struct {
+		a, b  string
+		match bool
+	}{
+		{"https://charts.example.com/foo", "https://charts.example.com/bar", true},
+		{"https://charts.example.com", "https://CHARTS.example.com", true},
+		{"https://charts.example.com:443", "https://charts.example.com", true},
+		{"https://charts.example.com", "https://evil.example.com", false},
+		{"https://charts.example.com:8080", "https://charts.example.com", false},
+	}
+
+	for _, c := range cases {
+		if got := hostsMatch(c.a, c.b); got != c.match {
+			t.Errorf("hostsMatch(%q, %q) = %v, want %v", c.a, c.b, got, c.match)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package chartmuseum
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ghodss/yaml"
+)
+
+type (
+	// Index is a minimal parse of a chart repository's index.yaml -
+	// enough to tell whether a given chart version is already present,
+	// and where to download it from
+	Index struct {
+		Entries map[string][]IndexChartVersion `json:"entries"`
+	}
+
+	// IndexChartVersion is a single entry of Index.Entries
+	IndexChartVersion struct {
+		Name    string   `json:"name"`
+		Version string   `json:"version"`
+		URLs    []string `json:"urls"`
+	}
+)
+
+// GetIndex fetches and parses index.yaml from the root of the repository
+func (c *Client) GetIndex() (*Index, error) {
+	resp, err := c.DownloadFileAt(c.URL + "/index.yaml")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch index.yaml: %d", resp.StatusCode)
+	}
+
+	var idx Index
+	if err := yaml.Unmarshal(b, &idx); err != nil {
+		return nil, err
+	}
+
+	return &idx, nil
+}
+
+// Has reports whether the index already contains name at version
+func (idx *Index) Has(name, version string) bool {
+	for _, v := range idx.Entries[name] {
+		if v.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// Missing returns every chart version in idx that other does not have.
+// other may be nil, in which case every version in idx is reported missing.
+func (idx *Index) Missing(other *Index) []IndexChartVersion {
+	var missing []IndexChartVersion
+	for name, versions := range idx.Entries {
+		for _, v := range versions {
+			if other != nil && other.Has(name, v.Version) {
+				continue
+			}
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,81 @@
+package chartmuseum
+
+import "testing"
+
+func TestIndexHas(t *testing.T) {
+	idx := &Index{Entries: map[string][]IndexChartVersion{
+		"mychart": {{Name: "mychart", Version: "0.1.0"}, {Name: "mychart", Version: "0.2.0"}},
+	}}
+
+	cases := []struct {
+		name, version string
+		want          bool
+	}{
+		{"mychart", "0.1.0", true},
+		{"mychart", "0.2.0", true},
+		{"mychart", "0.3.0", false},
+		{"otherchart", "0.1.0", false},
+	}
+
+	for _, c := range cases {
+		if got := idx.Has(c.name, c.version); got != c.want {
+			t.Errorf("Has(%q, %q) = %v, want %v", c.name, c.version, got, c.want)
+		}
+	}
+}
+
+func TestIndexMissing(t *testing.T) {
+	src := &Index{Entries: map[string][]IndexChartVersion{
+		"mychart": {{Name: "mychart", Version: "0.1.0"}, {Name: "mychart", Version: "0.2.0"}},
+		"other":   {{Name: "other", Version: "1.0.0"}},
+	}}
+
+	cases := []struct {
+		name  string
+		other *Index
+		want  []IndexChartVersion
+	}{
+		{
+			name:  "nil other reports everything missing",
+			other: nil,
+			want: []IndexChartVersion{
+				{Name: "mychart", Version: "0.1.0"}, {Name: "mychart", Version: "0.2.0"},
+				{Name: "other", Version: "1.0.0"},
+			},
+		},
+		{
+			name: "versions already present in other are excluded",
+			other: &Index{Entries: map[string][]IndexChartVersion{
+				"mychart": {{Name: "mychart", Version: "0.1.0"}},
+			}},
+			want: []IndexChartVersion{{Name: "mychart", Version: "0.2.0"}, {Name: "other", Version: "1.0.0"}},
+		},
+		{
+			name: "nothing missing when other already has everything",
+			other: &Index{Entries: map[string][]IndexChartVersion{
+				"mychart": {{Name: "mychart", Version: "0.1.0"}, {Name: "mychart", Version: "0.2.0"}},
+				"other":   {{Name: "other", Version: "1.0.0"}},
+			}},
+			want: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := src.Missing(c.other)
+			if len(got) != len(c.want) {
+				t.Fatalf("Missing() = %v entries, want %v", got, c.want)
+			}
+			type key struct{ name, version string }
+			seen := make(map[key]bool, len(got))
+			for _, v := range got {
+				seen[key{v.Name, v.Version}] = true
+			}
+			for _, v := range c.want {
+				if !seen[key{v.Name, v.Version}] {
+					t.Errorf("Missing() missing expected entry %v", v)
+				}
+			}
+		})
+	}
+}
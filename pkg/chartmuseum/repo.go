@@ -0,0 +1,25 @@
+package chartmuseum
+
+import "helm.sh/helm/v3/pkg/repo"
+
+// ClientFromRepoEntry builds a Client from a configured Helm repository
+// entry, picking up the URL, credentials, and any TLS material
+// (CAFile/CertFile/KeyFile/InsecureSkipTLSverify) the user already set
+// with `helm repo add`, so they don't need to be re-specified on the
+// command line. opts are applied after the entry's defaults and so take
+// precedence over them - callers use this to layer CLI flag and
+// environment variable overrides on top.
+func ClientFromRepoEntry(entry *repo.Entry, opts ...Option) (*Client, error) {
+	base := []Option{
+		URL(entry.URL),
+		SourceURL(entry.URL),
+		Username(entry.Username),
+		Password(entry.Password),
+		CAFile(entry.CAFile),
+		CertFile(entry.CertFile),
+		KeyFile(entry.KeyFile),
+		InsecureSkipVerify(entry.InsecureSkipTLSverify),
+	}
+
+	return NewClient(append(base, opts...)...)
+}
@@ -0,0 +1,206 @@
+package helm
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+type (
+	// Chart represents a local chart, either an unpacked directory
+	// or a path to an already-packaged .tgz
+	Chart struct {
+		Name    string
+		Version string
+		Path    string
+	}
+
+	chartMetadata struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+)
+
+// Repo is Helm's own repository entry type. Aliased here (rather than
+// redeclared) so that a repo configured via `helm repo add` - TLS
+// material included - is read natively, with no parallel struct for
+// GetRepoByName/cm.ClientFromRepoEntry to fall out of sync with.
+type Repo = repo.Entry
+
+// GetChartByName loads chart metadata from either a packaged .tgz
+// or an unpacked chart directory containing a Chart.yaml
+func GetChartByName(name string) (*Chart, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := &Chart{Path: name}
+
+	if !info.IsDir() {
+		chart.Name, chart.Version = chartNameVersionFromFilename(filepath.Base(name))
+		return chart, nil
+	}
+
+	meta, err := readChartMetadata(filepath.Join(name, "Chart.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	chart.Name = meta.Name
+	chart.Version = meta.Version
+	return chart, nil
+}
+
+// SetVersion overrides the version that will be used when the
+// chart is packaged, regardless of what is in Chart.yaml
+func (c *Chart) SetVersion(version string) {
+	c.Version = version
+}
+
+// CreateChartPackage packages the chart (if it is a directory) into
+// destDir, honoring any version override, and returns the path to
+// the resulting .tgz
+func CreateChartPackage(chart *Chart, destDir string) (string, error) {
+	info, err := os.Stat(chart.Path)
+	if err != nil {
+		return "", err
+	}
+
+	if !info.IsDir() {
+		return chart.Path, nil
+	}
+
+	args := []string{"package", chart.Path, "--destination", destDir}
+	if chart.Version != "" {
+		args = append(args, "--version", chart.Version)
+	}
+
+	out, err := exec.Command("helm", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("helm package failed: %s: %s", err, string(out))
+	}
+
+	return findPackagedChart(destDir, chart.Name)
+}
+
+// TempRepoFromURL builds a throwaway Repo for a bare repository URL,
+// used when the caller passes a URL directly instead of a configured
+// repo name
+func TempRepoFromURL(url string) (*Repo, error) {
+	return &Repo{URL: url}, nil
+}
+
+// GetRepoByName looks up a configured repository entry by name, reading
+// Helm's own repository file (respecting $HELM_REPOSITORY_CONFIG same as
+// the helm CLI) via pkg/repo rather than parsing it by hand.
+func GetRepoByName(name string) (*Repo, error) {
+	settings := cli.New()
+
+	rf, err := repo.LoadFile(settings.RepositoryConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not read repository file (%s): %s", settings.RepositoryConfig, err)
+	}
+
+	for _, entry := range rf.Repositories {
+		if entry.Name == name {
+			return entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("repo %q does not exist", name)
+}
+
+// ReadChartYAMLFromPackage extracts the raw Chart.yaml contents from a
+// packaged chart tarball, for callers (signing, OCI config blobs) that
+// need the metadata without unpacking the whole chart to disk
+func ReadChartYAMLFromPackage(chartPackagePath string) ([]byte, error) {
+	f, err := os.Open(chartPackagePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if filepath.Base(hdr.Name) == "Chart.yaml" {
+			return ioutil.ReadAll(tr)
+		}
+	}
+
+	return nil, fmt.Errorf("Chart.yaml not found in %s", chartPackagePath)
+}
+
+func readChartMetadata(chartYamlPath string) (*chartMetadata, error) {
+	b, err := ioutil.ReadFile(chartYamlPath)
+	if err != nil {
+		return nil, err
+	}
+	var meta chartMetadata
+	if err := yaml.Unmarshal(b, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// semverSuffix matches a dash-delimited suffix that looks like a full
+// semver version (optionally with a prerelease/build tag), anchored to
+// the end of the string.
+var semverSuffix = regexp.MustCompile(`^\d+\.\d+\.\d+([-+].*)?$`)
+
+// chartNameVersionFromFilename splits a packaged chart's filename into
+// name and version, following the "<name>-<version>.tgz" convention
+// `helm package` itself uses. The version starts at the rightmost dash
+// whose remainder looks like a full semver - scanning from the right
+// (rather than stopping at the first dash followed by a digit) means a
+// name containing its own dash+digit segment, such as "php-7-fpm", is
+// not mistaken for the start of the version.
+func chartNameVersionFromFilename(filename string) (string, string) {
+	base := strings.TrimSuffix(filename, filepath.Ext(filename))
+	if strings.HasSuffix(filename, ".tar.gz") {
+		base = strings.TrimSuffix(base, ".tar")
+	}
+
+	for i := len(base) - 1; i > 0; i-- {
+		if base[i] == '-' && semverSuffix.MatchString(base[i+1:]) {
+			return base[:i], base[i+1:]
+		}
+	}
+
+	return base, ""
+}
+
+func findPackagedChart(dir, chartName string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, chartName+"-*.tgz"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("could not find packaged chart for %q in %s", chartName, dir)
+	}
+	return matches[0], nil
+}
@@ -0,0 +1,28 @@
+package helm
+
+import "testing"
+
+func TestChartNameVersionFromFilename(t *testing.T) {
+	cases := []struct {
+		filename    string
+		wantName    string
+		wantVersion string
+	}{
+		{"mychart-0.1.0.tgz", "mychart", "0.1.0"},
+		{"mychart-0.1.0.tar.gz", "mychart", "0.1.0"},
+		{"mychart-1.2.3-beta.1.tgz", "mychart", "1.2.3-beta.1"},
+		{"mychart-1.2.3+build.5.tgz", "mychart", "1.2.3+build.5"},
+		{"php-7-fpm-2.5.0.tgz", "php-7-fpm", "2.5.0"},
+		{"foo-1-0.tgz", "foo-1-0", ""},
+		{"mychart.tgz", "mychart", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.filename, func(t *testing.T) {
+			name, version := chartNameVersionFromFilename(c.filename)
+			if name != c.wantName || version != c.wantVersion {
+				t.Errorf("chartNameVersionFromFilename(%q) = (%q, %q), want (%q, %q)", c.filename, name, version, c.wantName, c.wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package helm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+// SignChartPackage signs the chart tarball at chartPackagePath with the
+// named key from the secret keyring at keyringPath, decrypting the key
+// with passphrase if needed, and writes a sibling <chart>.tgz.prov file
+// in Helm's provenance format: a clearsigned block over the chart's
+// Chart.yaml contents, a YAML document-end marker ("..."), and the
+// SHA-256 digest of the tarball. The resulting file verifies with
+// `helm verify`.
+func SignChartPackage(chartPackagePath, keyName, keyringPath, passphrase string) (string, error) {
+	entity, err := findSigningKey(keyringPath, keyName, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	chartYaml, err := ReadChartYAMLFromPackage(chartPackagePath)
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := sha256File(chartPackagePath)
+	if err != nil {
+		return "", err
+	}
+
+	message := fmt.Sprintf("%s\n...\nfiles:\n  %s: sha256:%s\n", string(chartYaml), filepath.Base(chartPackagePath), digest)
+
+	var buf bytes.Buffer
+	w, err := clearsign.Encode(&buf, entity.PrivateKey, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not sign provenance: %s", err)
+	}
+	if _, err := w.Write([]byte(message)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	provPath := chartPackagePath + ".prov"
+	if err := ioutil.WriteFile(provPath, buf.Bytes(), 0644); err != nil {
+		return "", err
+	}
+
+	return provPath, nil
+}
+
+// VerifyChartPackage checks that provPath is a provenance file for
+// chartPackagePath, signed by a key present in keyringPath, and that
+// the digest it records matches the tarball on disk.
+func VerifyChartPackage(chartPackagePath, provPath, keyringPath string) error {
+	provBytes, err := ioutil.ReadFile(provPath)
+	if err != nil {
+		return err
+	}
+
+	kf, err := os.Open(keyringPath)
+	if err != nil {
+		return err
+	}
+	defer kf.Close()
+
+	keyring, err := openpgp.ReadKeyRing(kf)
+	if err != nil {
+		return err
+	}
+
+	block, _ := clearsign.Decode(provBytes)
+	if block == nil {
+		return fmt.Errorf("%s is not a valid provenance file", provPath)
+	}
+
+	if _, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body); err != nil {
+		return fmt.Errorf("signature verification failed: %s", err)
+	}
+
+	digest, err := sha256File(chartPackagePath)
+	if err != nil {
+		return err
+	}
+	if !bytes.Contains(block.Plaintext, []byte("sha256:"+digest)) {
+		return fmt.Errorf("sha256 sum of %s does not match the digest recorded in its provenance", filepath.Base(chartPackagePath))
+	}
+
+	return nil
+}
+
+func findSigningKey(keyringPath, keyName, passphrase string) (*openpgp.Entity, error) {
+	f, err := os.Open(keyringPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	keyring, err := openpgp.ReadKeyRing(f)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entity := range keyring {
+		if !entityMatchesName(entity, keyName) {
+			continue
+		}
+		if entity.PrivateKey == nil {
+			return nil, fmt.Errorf("key %q has no private key material", keyName)
+		}
+		if entity.PrivateKey.Encrypted {
+			if err := entity.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+				return nil, fmt.Errorf("could not decrypt private key for %q: %s", keyName, err)
+			}
+		}
+		return entity, nil
+	}
+
+	return nil, fmt.Errorf("key %q not found in keyring %s", keyName, keyringPath)
+}
+
+func entityMatchesName(entity *openpgp.Entity, name string) bool {
+	for id := range entity.Identities {
+		if strings.Contains(id, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,237 @@
+// Package ocipush pushes and pulls packaged Helm charts to/from an OCI
+// registry, following the Helm OCI support media types.
+package ocipush
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/retry"
+)
+
+const (
+	// ChartLayerMediaType is the media type of the packaged chart
+	// tarball layer, per Helm's OCI support spec
+	ChartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	// ConfigMediaType is the media type of the Chart.yaml-derived
+	// config blob every chart manifest carries
+	ConfigMediaType = "application/vnd.cncf.helm.config.v1+json"
+)
+
+type (
+	// Client pushes and pulls charts to/from a single OCI registry
+	Client struct {
+		Username    string
+		Password    string
+		AccessToken string
+		PlainHTTP   bool
+	}
+
+	// Option configures a Client
+	Option func(*Client)
+)
+
+// Username sets the registry basic auth username
+func Username(username string) Option {
+	return func(c *Client) { c.Username = username }
+}
+
+// Password sets the registry basic auth password
+func Password(password string) Option {
+	return func(c *Client) { c.Password = password }
+}
+
+// AccessToken sets a bearer/refresh token to authenticate with instead
+// of a username/password pair
+func AccessToken(accessToken string) Option {
+	return func(c *Client) { c.AccessToken = accessToken }
+}
+
+// PlainHTTP disables TLS, for registries running on plain HTTP (e.g. a
+// local `docker run registry:2`)
+func PlainHTTP(plain bool) Option {
+	return func(c *Client) { c.PlainHTTP = plain }
+}
+
+// NewClient constructs a Client for registry, falling back to the
+// credentials stored in Helm's registry config
+// (~/.config/helm/registry/config.json, Docker's config.json format)
+// when none of opts supply a username/password/access token.
+func NewClient(registry string, opts ...Option) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.Username == "" && c.Password == "" && c.AccessToken == "" {
+		if username, password, ok := credentialsFromConfigFile(registry); ok {
+			c.Username, c.Password = username, password
+		}
+	}
+
+	return c
+}
+
+// Push packages chartPackagePath (an already-built chart tarball) plus
+// chartYAML, converted to JSON, as the config blob, and pushes them as a single-layer OCI
+// artifact to ref (e.g. "registry.example.com/charts/mychart:1.2.3").
+// It returns the digest of the pushed manifest.
+func (c *Client) Push(ctx context.Context, chartPackagePath string, chartYAML []byte, ref string) (string, error) {
+	repo, err := c.repository(ref)
+	if err != nil {
+		return "", err
+	}
+
+	store, err := file.New(filepath.Dir(chartPackagePath))
+	if err != nil {
+		return "", err
+	}
+	defer store.Close()
+
+	chartDesc, err := store.Add(ctx, filepath.Base(chartPackagePath), ChartLayerMediaType, "")
+	if err != nil {
+		return "", err
+	}
+
+	config, err := yaml.YAMLToJSON(chartYAML)
+	if err != nil {
+		return "", err
+	}
+
+	configDesc, err := oras.PushBytes(ctx, store, ConfigMediaType, config)
+	if err != nil {
+		return "", err
+	}
+
+	manifestDesc, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, ConfigMediaType, oras.PackManifestOptions{
+		ConfigDescriptor: &configDesc,
+		Layers:           []ocispec.Descriptor{chartDesc},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	tag := repo.Reference.Reference
+	if err := store.Tag(ctx, manifestDesc, tag); err != nil {
+		return "", err
+	}
+
+	if _, err := oras.Copy(ctx, store, tag, repo, tag, oras.DefaultCopyOptions); err != nil {
+		return "", err
+	}
+
+	return manifestDesc.Digest.String(), nil
+}
+
+// Pull fetches the manifest at ref and returns the bytes of its chart
+// tarball layer
+func (c *Client) Pull(ctx context.Context, ref string) ([]byte, error) {
+	repo, err := c.repository(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	_, manifestBytes, err := oras.FetchBytes(ctx, repo, repo.Reference.Reference, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, err
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != ChartLayerMediaType {
+			continue
+		}
+		_, b, err := oras.FetchBytes(ctx, repo, layer.Digest.String(), oras.DefaultFetchBytesOptions)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("%s has no %s layer", ref, ChartLayerMediaType)
+}
+
+func (c *Client) repository(ref string) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	repo.PlainHTTP = c.PlainHTTP
+	repo.Client = &auth.Client{
+		Client: retry.DefaultClient,
+		Cache:  auth.DefaultCache,
+		Credential: auth.StaticCredential(repo.Reference.Registry, auth.Credential{
+			Username:     c.Username,
+			Password:     c.Password,
+			RefreshToken: c.AccessToken,
+		}),
+	}
+
+	return repo, nil
+}
+
+func credentialsFromConfigFile(registry string) (username, password string, ok bool) {
+	path, err := registryConfigPath()
+	if err != nil {
+		return "", "", false
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", false
+	}
+
+	var cfg struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return "", "", false
+	}
+
+	entry, found := cfg.Auths[registry]
+	if !found {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+	if err != nil {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func registryConfigPath() (string, error) {
+	if v := os.Getenv("HELM_REGISTRY_CONFIG"); v != "" {
+		return v, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "helm", "registry", "config.json"), nil
+}